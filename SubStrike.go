@@ -3,26 +3,65 @@ package main
 import (
 	"bufio"
 	"context"
+	"crypto/rand"
+	"crypto/sha1"
 	"crypto/tls"
 	"flag"
 	"fmt"
+	"io"
+	"math/big"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
 )
 
 type SubCombination struct {
-	wordlist   string
-	timeout    time.Duration
-	client     *http.Client
-	resolver   *net.Resolver
-	maxWorkers int
+	wordlist        string
+	timeout         time.Duration
+	client          *http.Client
+	resolver        dnsResolver
+	maxWorkers      int
+	noWildcardCheck bool
+	ctEnabled       bool
+	ctOnly          bool
+	httpEngine      httpProber
+
+	recursionDepth int
+	maxCandidates  int64
+	visited        sync.Map
+	generatedCount int64
+
+	rateLimiter  *rate.Limiter
+	resumeState  *scanState
+	rootProgress sync.Map // root string -> *rootProgress
+	outputFile   string
+
+	wildcardMu       sync.RWMutex
+	wildcardProfiles map[string]*wildcardProfile
+	wildcardChecks   sync.Map // root string -> *int64
+}
+
+// wildcardProfile captura a assinatura de uma zona DNS com wildcard: o
+// conjunto de IPs devolvido para labels aleatórios e a "impressão digital"
+// da resposta HTTP correspondente, para que candidatos legítimos não sejam
+// descartados por engano.
+type wildcardProfile struct {
+	ips      map[string]struct{}
+	httpSigs map[string]string // scheme -> "status:length:sha1(first N bytes)"
 }
 
+const wildcardFingerprintBytes = 2048
+const wildcardReprobeEvery = 5000
+
 type ProgressTracker struct {
 	total     int64
 	completed int64
@@ -61,18 +100,25 @@ func (pt *ProgressTracker) Increment() {
 	atomic.AddInt64(&pt.completed, 1)
 }
 
+// AddTotal cresce o total em `delta`, usado quando a recursão enfileira
+// novos candidatos depois que o scan já começou.
+func (pt *ProgressTracker) AddTotal(delta int64) {
+	atomic.AddInt64(&pt.total, delta)
+}
+
 func (pt *ProgressTracker) Stop() {
 	close(pt.done)
 }
 
 func (pt *ProgressTracker) display() {
 	completed := atomic.LoadInt64(&pt.completed)
-	percentage := float64(completed) / float64(pt.total) * 100
+	total := atomic.LoadInt64(&pt.total)
+	percentage := float64(completed) / float64(total) * 100
 	elapsed := time.Since(pt.startTime)
 
 	var eta time.Duration
 	if completed > 0 {
-		remainingItems := pt.total - completed
+		remainingItems := total - completed
 		timePerItem := elapsed / time.Duration(completed)
 		eta = timePerItem * time.Duration(remainingItems)
 	}
@@ -82,9 +128,9 @@ func (pt *ProgressTracker) display() {
 	barWidth := 40
 	filledWidth := int(float64(barWidth) * percentage / 100)
 	bar := strings.Repeat("█", filledWidth) + strings.Repeat("▒", barWidth-filledWidth)
-	
+
 	fmt.Printf("\r[%s] %.1f%% (%d/%d) | %.1f/s | ETA: %v | Elapsed: %v",
-		bar, percentage, completed, pt.total, rate, eta.Round(time.Second), elapsed.Round(time.Second))
+		bar, percentage, completed, total, rate, eta.Round(time.Second), elapsed.Round(time.Second))
 }
 
 func NewSubCombination() *SubCombination {
@@ -123,13 +169,16 @@ func NewSubCombination() *SubCombination {
 		},
 	}
 
-	return &SubCombination{
-		wordlist:   "",
-		timeout:    3 * time.Second,
-		client:     client,
-		resolver:   resolver,
-		maxWorkers: 300,
+	sc := &SubCombination{
+		wordlist:         "",
+		timeout:          3 * time.Second,
+		client:           client,
+		resolver:         &stdResolver{resolver: resolver},
+		maxWorkers:       300,
+		wildcardProfiles: make(map[string]*wildcardProfile),
 	}
+	sc.httpEngine = httpEngines["std"](sc)
+	return sc
 }
 
 func (sc *SubCombination) wordList(filename string) ([]string, error) {
@@ -188,72 +237,319 @@ func (sc *SubCombination) generateSubdomains(sub string, words []string, debug b
 	return subdomains
 }
 
-// Verifica DNS 
-func (sc *SubCombination) checkDNS(domain string, debug bool) bool {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+// recursiveChildren gera candidatos de um nível mais profundo que o host
+// vivo, prepending cada word em frente a found (ex: found="shop.example.com"
+// + word="api" -> "api.shop.example.com"). generateSubdomains não serve
+// aqui: ela preserva os dois últimos rótulos (o apex) e só insere entre
+// rótulos já existentes, então rodar um host encontrado por ela produziria
+// um irmão sob o apex em vez de um filho real de found.
+func recursiveChildren(found string, words []string) []string {
+	children := make([]string, 0, len(words))
+	for _, word := range words {
+		children = append(children, word+"."+found)
+	}
+	return children
+}
+
+// Verifica DNS
+func (sc *SubCombination) checkDNS(parent context.Context, domain string, debug bool) ([]string, bool) {
+	ctx, cancel := context.WithTimeout(parent, 2*time.Second)
+	defer cancel()
+
+	ips, err := sc.resolver.LookupHost(ctx, domain)
+	if err != nil {
+		if debug {
+			fmt.Printf("[DEBUG] DNS failed for %s\n", domain)
+		}
+		return nil, false
+	}
+	return ips, true
+}
+
+// randomHexLabel gera um label de alta entropia (ex: 32 chars hex) usado
+// para sondar zonas com wildcard DNS sem colidir com nomes reais.
+func randomHexLabel(n int) string {
+	const hexChars = "0123456789abcdef"
+	label := make([]byte, n)
+	for i := range label {
+		idx, err := rand.Int(rand.Reader, big.NewInt(int64(len(hexChars))))
+		if err != nil {
+			label[i] = hexChars[0]
+			continue
+		}
+		label[i] = hexChars[idx.Int64()]
+	}
+	return string(label)
+}
+
+// fingerprintHTTP faz um GET em scheme://domain/ e resume a resposta como
+// "status:length:sha1(primeiros N bytes)" para comparação posterior.
+func (sc *SubCombination) fingerprintHTTP(scheme, domain string) (string, bool) {
+	url := scheme + domain + "/"
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", false
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/138.0.0.0 Safari/537.36")
+
+	ctx, cancel := context.WithTimeout(context.Background(), sc.timeout)
 	defer cancel()
+	req = req.WithContext(ctx)
 
-	_, err := sc.resolver.LookupHost(ctx, domain)
-	if err != nil && debug {
-		fmt.Printf("[DEBUG] DNS failed for %s\n", domain)
+	resp, err := sc.client.Do(req)
+	if err != nil {
+		return "", false
 	}
-	return err == nil
+	defer resp.Body.Close()
+
+	body := make([]byte, wildcardFingerprintBytes)
+	n, _ := io.ReadFull(resp.Body, body)
+	sum := sha1.Sum(body[:n])
+	sig := fmt.Sprintf("%d:%d:%x", resp.StatusCode, resp.ContentLength, sum)
+	return sig, true
 }
 
-func (sc *SubCombination) checkSubdomainAlive(domain string, progress *ProgressTracker, debug bool) *string {
+// probeWildcard sonda o root com labels aleatórios e grava um wildcardProfile
+// com os IPs e assinaturas HTTP observados, para que checkSubdomainAlive
+// possa filtrar falsos positivos de zonas com wildcard DNS.
+func (sc *SubCombination) probeWildcard(root string, debug bool) {
+	if sc.noWildcardCheck {
+		return
+	}
+
+	profile := &wildcardProfile{
+		ips:      make(map[string]struct{}),
+		httpSigs: make(map[string]string),
+	}
+
+	probes := 3 + int(time.Now().UnixNano()%3) // 3-5 probes
+	for i := 0; i < probes; i++ {
+		label := randomHexLabel(32)
+		candidate := label + "." + root
+
+		ips, ok := sc.checkDNS(context.Background(), candidate, debug)
+		if !ok {
+			continue
+		}
+		for _, ip := range ips {
+			profile.ips[ip] = struct{}{}
+		}
+
+		for _, scheme := range []string{"https://", "http://"} {
+			if sig, ok := sc.fingerprintHTTP(scheme, candidate); ok {
+				profile.httpSigs[scheme] = sig
+			}
+		}
+	}
+
+	if len(profile.ips) == 0 {
+		return
+	}
+
+	if debug {
+		fmt.Printf("[DEBUG] Wildcard DNS detected for %s (%d IPs)\n", root, len(profile.ips))
+	}
+
+	sc.wildcardMu.Lock()
+	sc.wildcardProfiles[root] = profile
+	sc.wildcardMu.Unlock()
+}
+
+// incWildcardChecks incrementa e devolve o contador de checagens feitas
+// contra root, isolado por root: um scan com vários roots (-df) não pode
+// deixar roots com poucos candidatos nunca alcançarem o reprobe periódico
+// só porque o contador é dominado pelos roots com mais candidatos.
+func (sc *SubCombination) incWildcardChecks(root string) int64 {
+	counter, _ := sc.wildcardChecks.LoadOrStore(root, new(int64))
+	return atomic.AddInt64(counter.(*int64), 1)
+}
+
+// isWildcardMatch reporta se os IPs e a assinatura HTTP observados para um
+// candidato coincidem com o perfil wildcard já registrado para o root.
+func (sc *SubCombination) isWildcardMatch(root string, ips []string, scheme, domain string) bool {
+	sc.wildcardMu.RLock()
+	profile := sc.wildcardProfiles[root]
+	sc.wildcardMu.RUnlock()
+	if profile == nil || len(profile.ips) == 0 {
+		return false
+	}
+
+	for _, ip := range ips {
+		if _, ok := profile.ips[ip]; !ok {
+			return false
+		}
+	}
+
+	wantSig, ok := profile.httpSigs[scheme]
+	if !ok {
+		return false
+	}
+	gotSig, ok := sc.fingerprintHTTP(scheme, domain)
+	if !ok {
+		return false
+	}
+	return gotSig == wantSig
+}
+
+const titleProbeBytes = 8 * 1024
+
+func (sc *SubCombination) checkSubdomainAlive(parent context.Context, root, domain string, progress *ProgressTracker, debug bool) *HostResult {
 	defer progress.Increment()
 
+	if !sc.noWildcardCheck {
+		if n := sc.incWildcardChecks(root); n%wildcardReprobeEvery == 0 {
+			sc.probeWildcard(root, debug)
+		}
+	}
+
 	// Primeiro verifica DNS
-	if !sc.checkDNS(domain, debug) {
+	ips, ok := sc.checkDNS(parent, domain, debug)
+	if !ok {
 		return nil
 	}
 
+	result := &HostResult{
+		Domain:     domain,
+		IPs:        ips,
+		ResolvedAt: time.Now(),
+	}
+
+	cnameCtx, cnameCancel := context.WithTimeout(parent, 2*time.Second)
+	if cname, err := sc.resolver.LookupCNAME(cnameCtx, domain); err == nil {
+		result.CNAME = cname
+	}
+	cnameCancel()
+
+	alive := false
 	schemes := []string{"https://", "http://"}
 	for _, scheme := range schemes {
-		url := scheme + domain + "/"
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
+		if !sc.noWildcardCheck && sc.isWildcardMatch(root, ips, scheme, domain) {
 			if debug {
-				fmt.Printf("[DEBUG] Error creating GET request for %s: %v\n", url, err)
+				fmt.Printf("[DEBUG] Wildcard match, skipping %s\n", domain)
 			}
 			continue
 		}
 
-		req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/138.0.0.0 Safari/537.36")
-		req.Header.Set("Accept", "*/*")
-
-		ctx, cancel := context.WithTimeout(context.Background(), sc.timeout)
-		defer cancel()
-		req = req.WithContext(ctx)
-
-		resp, err := sc.client.Do(req)
+		ctx, cancel := context.WithTimeout(parent, sc.timeout)
+		resp, err := sc.httpEngine.Probe(ctx, scheme, domain)
+		cancel()
 		if err != nil {
 			if debug {
-				fmt.Printf("[DEBUG] Error on GET request for %s: %v\n", url, err)
+				fmt.Printf("[DEBUG] Error on GET request for %s%s/: %v\n", scheme, domain, err)
 			}
 			continue
 		}
-		defer resp.Body.Close()
+
+		alive = true
+		if scheme == "https://" {
+			result.HTTPSStatus = resp.StatusCode
+		} else {
+			result.HTTPStatus = resp.StatusCode
+		}
+		if result.Server == "" {
+			result.Server = resp.Server
+		}
+		if result.ContentLength == 0 {
+			result.ContentLength = resp.ContentLength
+		}
+		if title := extractTitle(resp.Body); title != "" && result.Title == "" {
+			result.Title = title
+		}
+		if result.TLSCertCN == "" {
+			result.TLSCertCN = resp.TLSCertCN
+			result.TLSCertSANs = resp.TLSCertSANs
+		}
 
 		fmt.Printf("\r%s\n", strings.Repeat(" ", 120))
 		fmt.Printf("[+] ONLINE: %s (%d)\n", domain, resp.StatusCode)
-		return &domain
 	}
 
-	return nil
+	if !alive {
+		return nil
+	}
+	return result
+}
+
+// candidate associa um subdomínio gerado ao seu domínio raiz, necessário
+// para consultar o wildcardProfile correto durante a checagem.
+type candidate struct {
+	root   string
+	domain string
+	depth  int
+	// index é a posição do candidato na sequência gerada para root no
+	// depth 0 (-1 para candidatos recursivos); usado por --resume.
+	index int
 }
 
-func (sc *SubCombination) bruteDomains(subList []string, debug bool) []string {
-	words, err := sc.wordList(sc.wordlist)
-	if err != nil {
-		fmt.Printf("Error reading wordlist: %v\n", err)
-		return nil
+// candidateQueue é uma fila FIFO de candidatos com buffer ilimitado: push
+// nunca bloqueia quem enfileira (inclusive workers recursando), enquanto
+// out entrega os itens, um de cada vez, para o pool fixo de workers.
+type candidateQueue struct {
+	in  chan candidate
+	out chan candidate
+}
+
+func newCandidateQueue() *candidateQueue {
+	q := &candidateQueue{in: make(chan candidate), out: make(chan candidate)}
+	go q.run()
+	return q
+}
+
+func (q *candidateQueue) push(c candidate) { q.in <- c }
+
+func (q *candidateQueue) close() { close(q.in) }
+
+func (q *candidateQueue) run() {
+	var buf []candidate
+	for {
+		if len(buf) == 0 {
+			c, ok := <-q.in
+			if !ok {
+				close(q.out)
+				return
+			}
+			buf = append(buf, c)
+			continue
+		}
+
+		select {
+		case c, ok := <-q.in:
+			if !ok {
+				for _, pending := range buf {
+					q.out <- pending
+				}
+				close(q.out)
+				return
+			}
+			buf = append(buf, c)
+		case q.out <- buf[0]:
+			buf = buf[1:]
+		}
+	}
+}
+
+func (sc *SubCombination) bruteDomains(ctx context.Context, subList []string, debug bool, rw resultWriter) int {
+	var words []string
+	if !sc.ctOnly {
+		var err error
+		words, err = sc.wordList(sc.wordlist)
+		if err != nil {
+			fmt.Printf("Error reading wordlist: %v\n", err)
+			return 0
+		}
+		fmt.Printf("[*] Loaded %d words from wordlist\n", len(words))
+	}
+
+	if !sc.noWildcardCheck {
+		fmt.Println("[*] Probing for wildcard DNS...")
+		for _, root := range subList {
+			sc.probeWildcard(root, debug)
+		}
 	}
 
-	fmt.Printf("[*] Loaded %d words from wordlist\n", len(words))
 	fmt.Println("[*] Generating subdomains...")
 
-	subdomainChan := make(chan []string, len(subList))
+	candidateChan := make(chan []candidate, len(subList))
 
 	var wg sync.WaitGroup
 	semaphore := make(chan struct{}, 10) // Limite para geração
@@ -265,80 +561,181 @@ func (sc *SubCombination) bruteDomains(subList []string, debug bool) []string {
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			results := sc.generateSubdomains(s, words, debug)
-			subdomainChan <- results
+			var passiveNames []string
+			if sc.ctEnabled || sc.ctOnly {
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				passiveNames = fetchPassiveNames(ctx, sc.client, s, debug)
+				cancel()
+				fmt.Printf("[*] Passive discovery found %d candidate names for %s\n", len(passiveNames), s)
+			}
+
+			var candidates []candidate
+			if sc.ctOnly {
+				candidates = make([]candidate, len(passiveNames))
+				for i, domain := range passiveNames {
+					candidates[i] = candidate{root: s, domain: domain, index: i}
+				}
+			} else {
+				rootWords := words
+				if len(passiveNames) > 0 {
+					rootWords = append(append([]string{}, words...), passiveLabels(passiveNames, s)...)
+				}
+				results := sc.generateSubdomains(s, rootWords, debug)
+				candidates = make([]candidate, len(results))
+				for i, domain := range results {
+					candidates[i] = candidate{root: s, domain: domain, index: i}
+				}
+			}
+			candidateChan <- candidates
 		}(sub)
 	}
 
 	// Goroutine para fechar o canal
 	go func() {
 		wg.Wait()
-		close(subdomainChan)
+		close(candidateChan)
 	}()
 
 	// Coleta todos os subdomínios
-	var allSubdomains []string
-	for results := range subdomainChan {
-		allSubdomains = append(allSubdomains, results...)
+	var allCandidates []candidate
+	for results := range candidateChan {
+		allCandidates = append(allCandidates, results...)
 	}
 
 	if debug {
-		fmt.Printf("\n[*] Generated %d subdomains (debug mode, exiting)\n", len(allSubdomains))
-		return allSubdomains
+		fmt.Printf("\n[*] Generated %d subdomains (debug mode, exiting)\n", len(allCandidates))
+		return 0
 	}
 
-	fmt.Printf("\n[*] Checking %d subdomains...\n", len(allSubdomains))
+	fmt.Printf("\n[*] Checking %d subdomains", len(allCandidates))
+	if sc.recursionDepth > 0 {
+		fmt.Printf(" (recursion up to depth %d)", sc.recursionDepth)
+	}
+	fmt.Println("...")
 
-	progress := NewProgressTracker(int64(len(allSubdomains)))
+	progress := NewProgressTracker(int64(len(allCandidates)))
 	progress.Start()
 
-	resultChan := make(chan string, sc.maxWorkers)
-	checkSemaphore := make(chan struct{}, sc.maxWorkers)
+	g, gctx := errgroup.WithContext(ctx)
 
-	wg = sync.WaitGroup{}
-	for _, domain := range allSubdomains {
-		wg.Add(1)
-		go func(d string) {
-			defer wg.Done()
-			checkSemaphore <- struct{}{}
-			defer func() { <-checkSemaphore }()
+	// queue desacopla quem enfileira candidatos (incluindo os próprios
+	// workers, ao recursar) de quem os consome: um único dispatcher com
+	// buffer ilimitado alimenta os sc.maxWorkers workers de tamanho fixo
+	// abaixo. Sem essa camada, um worker que tenta recursar via g.Go com
+	// o pool saturado bloqueia esperando um slot que só um worker ocupado
+	// poderia liberar — deadlock não interrompível por Ctrl-C.
+	queue := newCandidateQueue()
+	var pending sync.WaitGroup
+
+	var aliveCount int64
+
+	var enqueue func(c candidate)
+	enqueue = func(c candidate) {
+		if sc.maxCandidates > 0 && atomic.LoadInt64(&sc.generatedCount) >= sc.maxCandidates {
+			return
+		}
+		if _, loaded := sc.visited.LoadOrStore(c.domain, struct{}{}); loaded {
+			return
+		}
+		atomic.AddInt64(&sc.generatedCount, 1)
+
+		// Candidatos de depth 0 abaixo do índice salvo já foram processados
+		// na run anterior, então pulamos a checagem; note que o state file
+		// só guarda o índice e não quais hosts estavam vivos, então uma
+		// subárvore recursiva (--recursion-depth) originada de um host vivo
+		// antes da interrupção não é regenerada aqui.
+		if sc.resumeState != nil && c.depth == 0 && c.index >= 0 && c.index < sc.resumeState.LastIndex[c.root] {
+			sc.progressFor(c.root).markDone(c.index)
+			progress.Increment()
+			return
+		}
+
+		pending.Add(1)
+		queue.push(c)
+	}
+
+	for i := 0; i < sc.maxWorkers; i++ {
+		g.Go(func() error {
+			for c := range queue.out {
+				if gctx.Err() != nil {
+					// Não marca o índice como concluído: o candidato nunca
+					// foi de fato sondado, só drenado da fila ao cancelar.
+					// Marcá-lo deixaria --resume pular hosts nunca checados.
+					pending.Done()
+					continue
+				}
+				if sc.rateLimiter != nil {
+					if err := sc.rateLimiter.Wait(gctx); err != nil {
+						pending.Done()
+						continue
+					}
+				}
 
-			if result := sc.checkSubdomainAlive(d, progress, debug); result != nil {
-				resultChan <- *result
+				result := sc.checkSubdomainAlive(gctx, c.root, c.domain, progress, debug)
+				// Uma checagem interrompida pelo cancelamento também não conta
+				// como concluída, pelo mesmo motivo do dreno acima.
+				if c.depth == 0 && c.index >= 0 && gctx.Err() == nil {
+					sc.progressFor(c.root).markDone(c.index)
+				}
+				if result != nil {
+					atomic.AddInt64(&aliveCount, 1)
+					if err := rw.Write(result); err != nil {
+						fmt.Printf("[!] Error writing result for %s: %v\n", result.Domain, err)
+					}
+
+					if sc.recursionDepth > 0 && c.depth < sc.recursionDepth && gctx.Err() == nil {
+						children := recursiveChildren(c.domain, words)
+						progress.AddTotal(int64(len(children)))
+						for _, child := range children {
+							enqueue(candidate{root: c.root, domain: child, depth: c.depth + 1, index: -1})
+						}
+					}
+				}
+				pending.Done()
 			}
-		}(domain)
+			return nil
+		})
+	}
+
+	for _, c := range allCandidates {
+		enqueue(c)
 	}
 
 	go func() {
-		wg.Wait()
-		close(resultChan)
-		progress.Stop()
+		pending.Wait()
+		queue.close()
 	}()
 
-	var aliveSubdomains []string
-	for domain := range resultChan {
-		aliveSubdomains = append(aliveSubdomains, domain)
+	g.Wait()
+	progress.Stop()
+
+	if gctx.Err() != nil {
+		sc.saveResumeState(subList)
 	}
 
-	return aliveSubdomains
+	return int(aliveCount)
 }
 
-func (sc *SubCombination) writeOut(domains []string, filename string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
+// saveResumeState grava o progresso atual em "<output>.state" para que um
+// scan interrompido possa ser retomado com --resume.
+func (sc *SubCombination) saveResumeState(subList []string) {
+	var wordlistSHA1 string
+	if !sc.ctOnly {
+		var err error
+		wordlistSHA1, err = hashFile(sc.wordlist)
+		if err != nil {
+			fmt.Printf("[!] Error hashing wordlist, state not saved: %v\n", err)
+			return
+		}
 	}
-	defer file.Close()
 
-	writer := bufio.NewWriter(file)
-	defer writer.Flush()
-
-	for _, domain := range domains {
-		if _, err := writer.WriteString(domain + "\n"); err != nil {
-			return err
-		}
+	state := sc.snapshotState(wordlistSHA1, hashDomainList(subList))
+	path := stateFilePath(sc.outputFile)
+	if err := saveState(path, state); err != nil {
+		fmt.Printf("[!] Error saving state: %v\n", err)
+		return
 	}
-	return nil
+	fmt.Printf("\n[!] Scan interrupted; state saved to %s (use --resume %s to continue)\n", path, path)
 }
 
 func main() {
@@ -346,13 +743,24 @@ func main() {
 	wordlistFile := flag.String("w", "", "Wordlist file for subdomain generation")
 	debug := flag.Bool("debug", false, "Debug mode: only show subdomain combinations")
 	outputFile := flag.String("o", "resultado.txt", "Output file for results")
+	outputFormat := flag.String("of", "txt", "Output format: json, jsonl, csv or txt")
 	workers := flag.Int("workers", 300, "Number of concurrent workers")
 	timeout := flag.String("timeout", "3s", "Timeout for HTTP requests (e.g., 5s, 500ms)")
 	domainsFile := flag.String("df", "", "Arquivo com lista de domínios (um por linha)")
+	noWildcardCheck := flag.Bool("no-wildcard-check", false, "Disable wildcard DNS detection and filtering")
+	resolverMode := flag.String("resolver-mode", "udp", "DNS resolver engine: std (net.Resolver) or udp (miekg/dns pool)")
+	resolversFile := flag.String("resolvers", "", "File with upstream resolvers (host:port, one per line); defaults to public resolvers")
+	ct := flag.Bool("ct", false, "Seed subdomain generation with names discovered via Certificate Transparency logs")
+	ctOnly := flag.Bool("ct-only", false, "Skip brute-force generation; only probe names discovered via Certificate Transparency logs")
+	recursionDepth := flag.Int("recursion-depth", 0, "Recurse into alive subdomains up to N levels (0 = off); recursive subtrees are not regenerated on --resume, see its help text")
+	maxCandidates := flag.Int64("max-candidates", 0, "Cap the total number of generated candidates (0 = unlimited)")
+	httpEngine := flag.String("http-engine", "std", "HTTP probe engine: std (net/http) or fast (fasthttp, requires -tags fasthttp)")
+	rateFlag := flag.Float64("rate", 0, "Global query rate limit in queries/sec, shared across all workers (0 = unlimited)")
+	resumeFile := flag.String("resume", "", "Resume from a <output>.state file saved by a previous interrupted scan; the state file only tracks the last completed index per root, not which hosts were alive, so with --recursion-depth > 0 any recursive subtree spawned from a host found alive before the interruption is NOT regenerated on resume")
 	flag.Parse()
 
-	if *wordlistFile == "" {
-		fmt.Println("Error: You must provide a wordlist file (-w)")
+	if *wordlistFile == "" && !*ctOnly {
+		fmt.Println("Error: You must provide a wordlist file (-w), unless using --ct-only")
 		fmt.Println("Usage:")
 		flag.PrintDefaults()
 		os.Exit(1)
@@ -392,17 +800,90 @@ func main() {
 	sc.wordlist = *wordlistFile
 	sc.maxWorkers = *workers
 	sc.timeout = timeoutDuration
+	sc.noWildcardCheck = *noWildcardCheck
+	sc.ctEnabled = *ct
+	sc.ctOnly = *ctOnly
+	sc.recursionDepth = *recursionDepth
+	sc.maxCandidates = *maxCandidates
+	sc.outputFile = *outputFile
+
+	if *rateFlag > 0 {
+		sc.rateLimiter = rate.NewLimiter(rate.Limit(*rateFlag), int(*rateFlag)+1)
+	}
+
+	switch *resolverMode {
+	case "std":
+		// Mantém o stdResolver já configurado por NewSubCombination.
+	case "udp":
+		servers, err := loadResolvers(*resolversFile)
+		if err != nil {
+			fmt.Printf("Error loading resolvers: %v\n", err)
+			os.Exit(1)
+		}
+		sc.resolver = newDNSPool(servers, dnsPoolDefaultQPS)
+	default:
+		fmt.Printf("Error: Invalid resolver mode '%s'. Use 'std' or 'udp'.\n", *resolverMode)
+		os.Exit(1)
+	}
+
+	engineFactory, ok := httpEngines[*httpEngine]
+	if !ok {
+		fmt.Printf("Error: HTTP engine '%s' is not available in this build (compiled engines: %s)\n", *httpEngine, availableHTTPEngines())
+		os.Exit(1)
+	}
+	sc.httpEngine = engineFactory(sc)
+
+	if *resumeFile != "" {
+		state, err := loadState(*resumeFile)
+		if err != nil {
+			fmt.Printf("Error loading resume state from %s: %v\n", *resumeFile, err)
+			os.Exit(1)
+		}
+		if !*ctOnly {
+			wordlistSHA1, err := hashFile(*wordlistFile)
+			if err != nil {
+				fmt.Printf("Error hashing wordlist for --resume: %v\n", err)
+				os.Exit(1)
+			}
+			if wordlistSHA1 != state.WordlistSHA1 {
+				fmt.Println("Error: wordlist has changed since the saved state; --resume requires the same -w file")
+				os.Exit(1)
+			}
+		}
+		if hashDomainList(domains) != state.DomainsSHA1 {
+			fmt.Println("Error: domain list has changed since the saved state; --resume requires the same -d/-df input")
+			os.Exit(1)
+		}
+		sc.resumeState = state
+		fmt.Printf("[*] Resuming from %s\n", *resumeFile)
+		if sc.recursionDepth > 0 {
+			fmt.Println("[!] Warning: --resume only tracks the last completed index per root, not which hosts were alive; recursive subtrees from hosts found alive before the interruption will not be regenerated")
+		}
+	}
+
+	var rw resultWriter
+	if !*debug {
+		var err error
+		rw, err = newResultWriter(*outputFormat, *outputFile)
+		if err != nil {
+			fmt.Printf("Error opening output file: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	start := time.Now()
-	results := sc.bruteDomains(domains, *debug)
+	aliveCount := sc.bruteDomains(ctx, domains, *debug, rw)
 	elapsed := time.Since(start)
 
 	if !*debug {
-		if err := sc.writeOut(results, *outputFile); err != nil {
+		if err := rw.Close(); err != nil {
 			fmt.Printf("Error writing results: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Printf("\n[*] Found %d alive subdomains in %v\n", len(results), elapsed)
+		fmt.Printf("\n[*] Found %d alive subdomains in %v\n", aliveCount, elapsed)
 		fmt.Printf("[*] Results saved to %s\n", *outputFile)
 	}
 }
@@ -0,0 +1,385 @@
+package main
+
+import (
+	"bufio"
+	"container/list"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// defaultResolvers é usada quando o usuário não fornece --resolvers.
+var defaultResolvers = []string{
+	"8.8.8.8:53",
+	"1.1.1.1:53",
+	"9.9.9.9:53",
+	"208.67.222.222:53",
+}
+
+const (
+	dnsPoolQueryTimeout  = 2 * time.Second
+	dnsPoolMaxCNAMEHops  = 8
+	dnsPoolLRUCacheSize  = 50000
+	dnsPoolDefaultQPS    = 100
+	dnsPoolEjectAfter    = 3
+	dnsPoolEjectDuration = 30 * time.Second
+)
+
+// dnsResolver abstrai a resolução de hostnames, permitindo alternar entre o
+// resolver padrão do Go e o pool UDP baseado em miekg/dns via --resolver-mode.
+type dnsResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	LookupCNAME(ctx context.Context, host string) (string, error)
+}
+
+// stdResolver é o adaptador fino em torno de *net.Resolver, preservando o
+// comportamento original quando --resolver-mode=std.
+type stdResolver struct {
+	resolver *net.Resolver
+}
+
+func (r *stdResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	return r.resolver.LookupHost(ctx, host)
+}
+
+func (r *stdResolver) LookupCNAME(ctx context.Context, host string) (string, error) {
+	cname, err := r.resolver.LookupCNAME(ctx, host)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(cname, "."), nil
+}
+
+// tokenBucket é um limitador de taxa simples e independente por resolver
+// upstream (qps configurável, sem dependências externas).
+type tokenBucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	capacity float64
+	rate     float64 // tokens por segundo
+	last     time.Time
+}
+
+func newTokenBucket(qps int) *tokenBucket {
+	return &tokenBucket{
+		tokens:   float64(qps),
+		capacity: float64(qps),
+		rate:     float64(qps),
+		last:     time.Now(),
+	}
+}
+
+// take bloqueia até que um token esteja disponível ou o contexto seja cancelado.
+func (tb *tokenBucket) take(ctx context.Context) error {
+	for {
+		tb.mu.Lock()
+		now := time.Now()
+		elapsed := now.Sub(tb.last).Seconds()
+		tb.tokens += elapsed * tb.rate
+		if tb.tokens > tb.capacity {
+			tb.tokens = tb.capacity
+		}
+		tb.last = now
+
+		if tb.tokens >= 1 {
+			tb.tokens--
+			tb.mu.Unlock()
+			return nil
+		}
+		tb.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+// upstreamResolver agrupa o estado de saúde de um resolver DNS upstream:
+// limite de taxa e ejeção temporária após falhas consecutivas.
+type upstreamResolver struct {
+	addr        string
+	client      *dns.Client
+	bucket      *tokenBucket
+	failStreak  int32
+	ejectedTill atomic.Int64 // unix nano; 0 = não ejetado
+}
+
+func (u *upstreamResolver) ejected() bool {
+	till := u.ejectedTill.Load()
+	return till != 0 && time.Now().UnixNano() < till
+}
+
+func (u *upstreamResolver) recordSuccess() {
+	atomic.StoreInt32(&u.failStreak, 0)
+	u.ejectedTill.Store(0)
+}
+
+func (u *upstreamResolver) recordFailure() {
+	if atomic.AddInt32(&u.failStreak, 1) >= dnsPoolEjectAfter {
+		u.ejectedTill.Store(time.Now().Add(dnsPoolEjectDuration).UnixNano())
+	}
+}
+
+// lruCache é um cache FQDN -> []string com capacidade fixa e política LRU,
+// usado para não repetir lookups ao varrer muitos domínios raiz.
+type lruCache struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+type lruEntry struct {
+	key   string
+	value []string
+}
+
+func newLRUCache(capacity int) *lruCache {
+	return &lruCache{
+		capacity: capacity,
+		items:    make(map[string]*list.Element, capacity),
+		order:    list.New(),
+	}
+}
+
+func (c *lruCache) get(key string) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.order.MoveToFront(el)
+		return el.Value.(*lruEntry).value, true
+	}
+	return nil, false
+}
+
+func (c *lruCache) put(key string, value []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		el.Value.(*lruEntry).value = value
+		c.order.MoveToFront(el)
+		return
+	}
+	el := c.order.PushFront(&lruEntry{key: key, value: value})
+	c.items[key] = el
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.items, oldest.Value.(*lruEntry).key)
+		}
+	}
+}
+
+// dnsPool é um resolver concorrente baseado em UDP (miekg/dns) que faz
+// round-robin entre resolvers upstream, aplica limitação de taxa por
+// resolver, ejeta resolvers instáveis e segue cadeias de CNAME.
+type dnsPool struct {
+	upstreams []*upstreamResolver
+	next      uint64
+	cache     *lruCache
+}
+
+func newDNSPool(servers []string, qps int) *dnsPool {
+	upstreams := make([]*upstreamResolver, len(servers))
+	for i, addr := range servers {
+		upstreams[i] = &upstreamResolver{
+			addr: addr,
+			client: &dns.Client{
+				Net:          "udp",
+				ReadTimeout:  2 * time.Second,
+				WriteTimeout: 2 * time.Second,
+			},
+			bucket: newTokenBucket(qps),
+		}
+	}
+	return &dnsPool{
+		upstreams: upstreams,
+		cache:     newLRUCache(dnsPoolLRUCacheSize),
+	}
+}
+
+// loadResolvers lê um arquivo com um resolver "host:port" por linha. Um
+// caminho vazio resulta na lista de resolvers públicos padrão.
+func loadResolvers(path string) ([]string, error) {
+	if path == "" {
+		return defaultResolvers, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var servers []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			servers = append(servers, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("no resolvers found in %s", path)
+	}
+	return servers, nil
+}
+
+// pick escolhe o próximo resolver saudável em round-robin, pulando os que
+// estão temporariamente ejetados.
+func (p *dnsPool) pick() *upstreamResolver {
+	n := len(p.upstreams)
+	for i := 0; i < n; i++ {
+		idx := int(atomic.AddUint64(&p.next, 1)) % n
+		u := p.upstreams[idx]
+		if !u.ejected() {
+			return u
+		}
+	}
+	// Todos ejetados: usa o próximo da rotação mesmo assim.
+	idx := int(atomic.AddUint64(&p.next, 1)) % n
+	return p.upstreams[idx]
+}
+
+// queryOne faz uma única consulta (A ou AAAA) em um resolver e devolve os
+// IPs e o nome de CNAME seguinte, se houver.
+func (p *dnsPool) queryOne(ctx context.Context, u *upstreamResolver, host string, qtype uint16) ([]string, string, error) {
+	if err := u.bucket.take(ctx); err != nil {
+		return nil, "", err
+	}
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(host), qtype)
+	msg.RecursionDesired = true
+
+	resp, _, err := u.client.ExchangeContext(ctx, msg, u.addr)
+	if err != nil {
+		u.recordFailure()
+		return nil, "", err
+	}
+	if resp.Rcode == dns.RcodeRefused || resp.Rcode == dns.RcodeServerFailure {
+		u.recordFailure()
+		return nil, "", fmt.Errorf("resolver %s returned %s", u.addr, dns.RcodeToString[resp.Rcode])
+	}
+	u.recordSuccess()
+
+	var ips []string
+	var cname string
+	for _, rr := range resp.Answer {
+		switch rec := rr.(type) {
+		case *dns.A:
+			ips = append(ips, rec.A.String())
+		case *dns.AAAA:
+			ips = append(ips, rec.AAAA.String())
+		case *dns.CNAME:
+			cname = strings.TrimSuffix(rec.Target, ".")
+		}
+	}
+	return ips, cname, nil
+}
+
+// LookupCNAME consulta um único registro CNAME para host, sem seguir a
+// cadeia. Devolve "" (sem erro) quando o nome não tem CNAME.
+func (p *dnsPool) LookupCNAME(ctx context.Context, host string) (string, error) {
+	u := p.pick()
+	_, cname, err := p.queryOne(ctx, u, host, dns.TypeCNAME)
+	if err != nil {
+		return "", err
+	}
+	return cname, nil
+}
+
+// LookupHost resolve host para seus IPs (A e AAAA em paralelo), seguindo
+// cadeias de CNAME por até dnsPoolMaxCNAMEHops saltos, com cache LRU.
+func (p *dnsPool) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if ips, ok := p.cache.get(host); ok {
+		return ips, nil
+	}
+
+	current := host
+	var allIPs []string
+
+	for hop := 0; hop <= dnsPoolMaxCNAMEHops; hop++ {
+		type qResult struct {
+			ips   []string
+			cname string
+			err   error
+		}
+		results := make(chan qResult, 2)
+
+		var wg sync.WaitGroup
+		for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+			wg.Add(1)
+			go func(qtype uint16) {
+				defer wg.Done()
+				u := p.pick()
+				ips, cname, err := p.queryOne(ctx, u, current, qtype)
+				results <- qResult{ips: ips, cname: cname, err: err}
+			}(qtype)
+		}
+
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		var cname string
+		var gotAny bool
+		for res := range results {
+			if res.err == nil {
+				gotAny = true
+			}
+			allIPs = append(allIPs, res.ips...)
+			if res.cname != "" {
+				cname = res.cname
+			}
+		}
+
+		if !gotAny && len(allIPs) == 0 {
+			return nil, fmt.Errorf("dns lookup failed for %s", host)
+		}
+
+		if cname == "" || cname == current {
+			break
+		}
+		current = cname
+	}
+
+	if len(allIPs) == 0 {
+		return nil, fmt.Errorf("no A/AAAA records for %s", host)
+	}
+
+	allIPs = dedupeIPs(allIPs)
+	p.cache.put(host, allIPs)
+	return allIPs, nil
+}
+
+// dedupeIPs remove duplicatas preservando a ordem original. Necessário
+// porque um resolvedor pode devolver o CNAME e seus A/AAAA finais na mesma
+// resposta; ao seguir a cadeia, reconsultamos o nome terminal e os mesmos
+// registros acabam anexados de novo.
+func dedupeIPs(ips []string) []string {
+	seen := make(map[string]struct{}, len(ips))
+	out := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		if _, ok := seen[ip]; ok {
+			continue
+		}
+		seen[ip] = struct{}{}
+		out = append(out, ip)
+	}
+	return out
+}
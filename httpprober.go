@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// probeResponse resume o suficiente de uma resposta HTTP/HTTPS para
+// alimentar um HostResult, independente do motor HTTP usado (std ou fasthttp).
+type probeResponse struct {
+	StatusCode    int
+	Server        string
+	ContentLength int64
+	Body          []byte // até titleProbeBytes, para extração de <title>
+	TLSCertCN     string
+	TLSCertSANs   []string
+}
+
+// httpProber faz um GET em scheme://domain/ sem seguir redirecionamentos.
+// Duas implementações existem: stdHTTPProber (net/http, padrão) e
+// fastHTTPProber (valyala/fasthttp, compilado com -tags fasthttp).
+type httpProber interface {
+	Probe(ctx context.Context, scheme, domain string) (*probeResponse, error)
+}
+
+// httpEngines registra os motores HTTP disponíveis nesta build, indexados
+// pelo valor aceito por --http-engine.
+var httpEngines = map[string]func(sc *SubCombination) httpProber{}
+
+// availableHTTPEngines lista, em ordem estável, os nomes de engine
+// compilados nesta build (para mensagens de erro de --http-engine).
+func availableHTTPEngines() string {
+	names := make([]string, 0, len(httpEngines))
+	for name := range httpEngines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,89 @@
+//go:build fasthttp
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func init() {
+	httpEngines["fast"] = func(sc *SubCombination) httpProber {
+		return newFastHTTPProber(sc)
+	}
+}
+
+// fastHTTPProber implementa httpProber com valyala/fasthttp, reaproveitando
+// Request/Response acquirados do pool global para não alocar no hot path.
+type fastHTTPProber struct {
+	client  *fasthttp.Client
+	timeout time.Duration
+}
+
+func newFastHTTPProber(sc *SubCombination) *fastHTTPProber {
+	dialer := &net.Dialer{Timeout: sc.timeout}
+	return &fastHTTPProber{
+		client: &fasthttp.Client{
+			MaxConnsPerHost: sc.maxWorkers,
+			ReadTimeout:     sc.timeout,
+			WriteTimeout:    sc.timeout,
+			Dial: func(addr string) (net.Conn, error) {
+				return dialer.Dial("tcp", addr)
+			},
+			TLSConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+		},
+		timeout: sc.timeout,
+	}
+}
+
+func (p *fastHTTPProber) Probe(ctx context.Context, scheme, domain string) (*probeResponse, error) {
+	url := scheme + domain + "/"
+
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(url)
+	req.Header.SetMethod("GET")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/138.0.0.0 Safari/537.36")
+	req.Header.Set("Accept", "*/*")
+
+	timeout := p.deadlineFrom(ctx)
+	if err := p.client.DoTimeout(req, resp, timeout); err != nil {
+		return nil, err
+	}
+
+	body := resp.Body()
+	n := len(body)
+	if n > titleProbeBytes {
+		n = titleProbeBytes
+	}
+
+	// fasthttp não expõe o *tls.ConnectionState da conexão subjacente, então
+	// TLSCertCN/TLSCertSANs ficam vazios neste engine; use --http-engine std
+	// quando os dados de certificado forem necessários.
+	result := &probeResponse{
+		StatusCode:    resp.StatusCode(),
+		Server:        string(resp.Header.Peek("Server")),
+		ContentLength: int64(resp.Header.ContentLength()),
+		Body:          append([]byte(nil), body[:n]...),
+	}
+
+	return result, nil
+}
+
+func (p *fastHTTPProber) deadlineFrom(ctx context.Context) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		if d := time.Until(deadline); d > 0 {
+			return d
+		}
+	}
+	return p.timeout
+}
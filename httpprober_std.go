@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+func init() {
+	httpEngines["std"] = func(sc *SubCombination) httpProber {
+		return &stdHTTPProber{client: sc.client}
+	}
+}
+
+// stdHTTPProber implementa httpProber usando o *http.Client já configurado
+// em SubCombination (sem seguir redirecionamentos, InsecureSkipVerify).
+type stdHTTPProber struct {
+	client *http.Client
+}
+
+func (p *stdHTTPProber) Probe(ctx context.Context, scheme, domain string) (*probeResponse, error) {
+	url := scheme + domain + "/"
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/138.0.0.0 Safari/537.36")
+	req.Header.Set("Accept", "*/*")
+	req = req.WithContext(ctx)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body := make([]byte, titleProbeBytes)
+	n, _ := io.ReadFull(resp.Body, body)
+
+	result := &probeResponse{
+		StatusCode:    resp.StatusCode,
+		Server:        resp.Header.Get("Server"),
+		ContentLength: resp.ContentLength,
+		Body:          body[:n],
+	}
+
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		cert := resp.TLS.PeerCertificates[0]
+		result.TLSCertCN = cert.Subject.CommonName
+		result.TLSCertSANs = cert.DNSNames
+	}
+
+	return result, nil
+}
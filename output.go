@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// HostResult é o registro completo produzido por checkSubdomainAlive para
+// cada subdomínio vivo, usado pelos formatos de saída estruturados.
+type HostResult struct {
+	Domain        string    `json:"domain"`
+	IPs           []string  `json:"ips,omitempty"`
+	CNAME         string    `json:"cname,omitempty"`
+	HTTPStatus    int       `json:"http_status,omitempty"`
+	HTTPSStatus   int       `json:"https_status,omitempty"`
+	Server        string    `json:"server,omitempty"`
+	Title         string    `json:"title,omitempty"`
+	ContentLength int64     `json:"content_length"`
+	TLSCertCN     string    `json:"tls_cert_cn,omitempty"`
+	TLSCertSANs   []string  `json:"tls_cert_sans,omitempty"`
+	ResolvedAt    time.Time `json:"resolved_at"`
+}
+
+var titleRegexp = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// extractTitle procura a primeira tag <title> em até 8KB de corpo HTML.
+func extractTitle(body []byte) string {
+	match := titleRegexp.FindSubmatch(body)
+	if match == nil {
+		return ""
+	}
+	return string(match[1])
+}
+
+// resultWriter grava HostResults no formato escolhido por -of. Os formatos
+// jsonl, csv e txt vão ao disco assim que cada resultado chega, para não
+// perder dados em Ctrl-C; json acumula tudo e só grava em Close.
+type resultWriter interface {
+	Write(r *HostResult) error
+	Close() error
+}
+
+// newResultWriter abre filename no formato solicitado (json, jsonl, csv ou txt).
+func newResultWriter(format, filename string) (resultWriter, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case "json":
+		return &jsonArrayWriter{file: file}, nil
+	case "jsonl":
+		return &lineWriter{file: file, encode: func(r *HostResult) (string, error) {
+			b, err := json.Marshal(r)
+			if err != nil {
+				return "", err
+			}
+			return string(b), nil
+		}}, nil
+	case "csv":
+		w := &csvWriter{file: file, writer: csv.NewWriter(file)}
+		if err := w.writeHeader(); err != nil {
+			file.Close()
+			return nil, err
+		}
+		return w, nil
+	case "txt":
+		return &lineWriter{file: file, encode: func(r *HostResult) (string, error) {
+			return r.Domain, nil
+		}}, nil
+	default:
+		file.Close()
+		return nil, fmt.Errorf("unknown output format %q (use json, jsonl, csv or txt)", format)
+	}
+}
+
+// lineWriter grava um HostResult por linha, em texto simples ou em JSONL,
+// fazendo flush após cada gravação.
+type lineWriter struct {
+	mu     sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+	encode func(r *HostResult) (string, error)
+}
+
+func (w *lineWriter) Write(r *HostResult) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.writer == nil {
+		w.writer = bufio.NewWriter(w.file)
+	}
+
+	line, err := w.encode(r)
+	if err != nil {
+		return err
+	}
+	if _, err := w.writer.WriteString(line + "\n"); err != nil {
+		return err
+	}
+	return w.writer.Flush()
+}
+
+func (w *lineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.writer != nil {
+		w.writer.Flush()
+	}
+	return w.file.Close()
+}
+
+// csvWriter grava um HostResult por linha em CSV, com header fixo.
+type csvWriter struct {
+	mu     sync.Mutex
+	file   *os.File
+	writer *csv.Writer
+}
+
+func (w *csvWriter) writeHeader() error {
+	return w.writer.Write([]string{
+		"domain", "ips", "cname", "http_status", "https_status",
+		"server", "title", "content_length", "tls_cert_cn", "tls_cert_sans", "resolved_at",
+	})
+}
+
+func (w *csvWriter) Write(r *HostResult) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	record := []string{
+		r.Domain,
+		joinOrEmpty(r.IPs),
+		r.CNAME,
+		strconv.Itoa(r.HTTPStatus),
+		strconv.Itoa(r.HTTPSStatus),
+		r.Server,
+		r.Title,
+		strconv.FormatInt(r.ContentLength, 10),
+		r.TLSCertCN,
+		joinOrEmpty(r.TLSCertSANs),
+		r.ResolvedAt.Format(time.RFC3339),
+	}
+	if err := w.writer.Write(record); err != nil {
+		return err
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvWriter) Close() error {
+	return w.file.Close()
+}
+
+func joinOrEmpty(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	out := values[0]
+	for _, v := range values[1:] {
+		out += ";" + v
+	}
+	return out
+}
+
+// jsonArrayWriter acumula os resultados e grava um array JSON único ao
+// fechar, já que um array JSON não pode ser transmitido incrementalmente
+// sem ficar inválido até o final do scan.
+type jsonArrayWriter struct {
+	mu      sync.Mutex
+	file    *os.File
+	results []*HostResult
+}
+
+func (w *jsonArrayWriter) Write(r *HostResult) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.results = append(w.results, r)
+	return nil
+}
+
+func (w *jsonArrayWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	enc := json.NewEncoder(w.file)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(w.results); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
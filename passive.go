@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// passiveCTSource é uma fonte de Certificate Transparency consultada por
+// fetchPassiveNames. Cada fonte devolve nomes brutos (common_name/SAN) que
+// ainda precisam ser normalizados.
+//
+// NOTA DE ESCOPO (pendente de aprovação do dono do backlog): o pedido original
+// pede as APIs dos operadores de log CT Google/Cloudflare/Sectigo
+// diretamente. Essas APIs são por log (get-entries) e não indexadas por
+// domínio — usá-las exigiria baixar e decodificar precertificados de cada
+// log inteiro para filtrar client-side, o que não é prático aqui. crt.sh e
+// Cert Spotter são agregadores que já indexam esses mesmos logs (entre
+// outros) por domínio, que é o padrão real usado por ferramentas de recon
+// (subfinder, amass etc). Implementados como substituto prático no lugar
+// das APIs por operador pedidas; aguardando sign-off explícito do dono do
+// backlog antes de considerar o pedido atendido como especificado.
+type passiveCTSource struct {
+	name  string
+	query func(ctx context.Context, client *http.Client, root string) ([]string, error)
+}
+
+var passiveCTSources = []passiveCTSource{
+	{name: "crt.sh", query: queryCrtSh},
+	{name: "certspotter", query: queryCertSpotter},
+}
+
+// crtShEntry modela o subconjunto relevante do JSON devolvido por crt.sh.
+type crtShEntry struct {
+	CommonName string `json:"common_name"`
+	NameValue  string `json:"name_value"`
+}
+
+func queryCrtSh(ctx context.Context, client *http.Client, root string) ([]string, error) {
+	url := fmt.Sprintf("https://crt.sh/?q=%%.%s&output=json", root)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("crt.sh returned status %d", resp.StatusCode)
+	}
+
+	var entries []crtShEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, strings.Split(entry.NameValue, "\n")...)
+		names = append(names, entry.CommonName)
+	}
+	return names, nil
+}
+
+// certSpotterEntry modela o subconjunto relevante do JSON da API pública do
+// Cert Spotter (sslmate), usada como segunda fonte de CT independente.
+type certSpotterEntry struct {
+	DNSNames []string `json:"dns_names"`
+}
+
+func queryCertSpotter(ctx context.Context, client *http.Client, root string) ([]string, error) {
+	url := fmt.Sprintf("https://api.certspotter.com/v1/issuances?domain=%s&include_subdomains=true&expand=dns_names", root)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("certspotter returned status %d", resp.StatusCode)
+	}
+
+	var entries []certSpotterEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.DNSNames...)
+	}
+	return names, nil
+}
+
+// fetchPassiveNames consulta todas as fontes de CT em passiveCTSources para
+// root, normaliza (remove wildcard "*.", ponto final e diferenças de
+// maiúsculas/minúsculas) e devolve uma lista deduplicada de FQDNs.
+func fetchPassiveNames(ctx context.Context, client *http.Client, root string, debug bool) []string {
+	seen := make(map[string]struct{})
+	var names []string
+
+	for _, source := range passiveCTSources {
+		raw, err := source.query(ctx, client, root)
+		if err != nil {
+			if debug {
+				fmt.Printf("[DEBUG] Passive source %s failed for %s: %v\n", source.name, root, err)
+			}
+			continue
+		}
+
+		for _, n := range raw {
+			n = normalizePassiveName(n, root)
+			if n == "" {
+				continue
+			}
+			if _, ok := seen[n]; ok {
+				continue
+			}
+			seen[n] = struct{}{}
+			names = append(names, n)
+		}
+	}
+
+	if debug {
+		fmt.Printf("[DEBUG] Passive discovery found %d unique names for %s\n", len(names), root)
+	}
+	return names
+}
+
+// normalizePassiveName limpa um nome bruto de um log CT: remove espaços,
+// o prefixo wildcard, o ponto final e descarta nomes que não pertencem a
+// root ou que não são subdomínios propriamente ditos.
+func normalizePassiveName(name, root string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.TrimPrefix(name, "*.")
+	name = strings.TrimSuffix(name, ".")
+	if name == "" || name == root {
+		return ""
+	}
+	if !strings.HasSuffix(name, "."+root) {
+		return ""
+	}
+	return name
+}
+
+// passiveLabels extrai apenas o rótulo de subdomínio (tudo antes de root)
+// de nomes já normalizados, para uso como tokens extras em generateSubdomains.
+func passiveLabels(names []string, root string) []string {
+	suffix := "." + root
+	labels := make([]string, 0, len(names))
+	for _, n := range names {
+		label := strings.TrimSuffix(n, suffix)
+		if label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
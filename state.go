@@ -0,0 +1,119 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+)
+
+// scanState é persistido em "<output>.state" quando um scan é interrompido,
+// e recarregado via --resume para pular candidatos já verificados.
+type scanState struct {
+	WordlistSHA1 string         `json:"wordlist_sha1"`
+	DomainsSHA1  string         `json:"domains_sha1"`
+	LastIndex    map[string]int `json:"last_index"` // root -> primeiro índice de depth 0 ainda não concluído (todos abaixo dele já terminaram)
+}
+
+func sha1Hex(data []byte) string {
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return sha1Hex(data), nil
+}
+
+func hashDomainList(domains []string) string {
+	return sha1Hex([]byte(strings.Join(domains, "\n")))
+}
+
+func stateFilePath(outputFile string) string {
+	return outputFile + ".state"
+}
+
+func saveState(path string, state *scanState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func loadState(path string) (*scanState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var state scanState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	if state.LastIndex == nil {
+		state.LastIndex = make(map[string]int)
+	}
+	return &state, nil
+}
+
+// rootProgress rastreia, para um root, quais índices de candidatos de
+// depth 0 já foram concluídos, avançando um marcador contíguo (committed)
+// que pode ser gravado com segurança no arquivo de estado: todo índice
+// abaixo dele já terminou, mesmo que a conclusão tenha sido fora de ordem.
+type rootProgress struct {
+	mu        sync.Mutex
+	completed map[int]struct{}
+	committed int
+}
+
+func newRootProgress() *rootProgress {
+	return &rootProgress{completed: make(map[int]struct{})}
+}
+
+func (rp *rootProgress) markDone(index int) {
+	if index < 0 {
+		return
+	}
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	rp.completed[index] = struct{}{}
+	for {
+		if _, ok := rp.completed[rp.committed]; !ok {
+			break
+		}
+		delete(rp.completed, rp.committed)
+		rp.committed++
+	}
+}
+
+func (rp *rootProgress) committedIndex() int {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	return rp.committed
+}
+
+// progressFor devolve (criando se necessário) o rootProgress de root.
+func (sc *SubCombination) progressFor(root string) *rootProgress {
+	v, _ := sc.rootProgress.LoadOrStore(root, newRootProgress())
+	return v.(*rootProgress)
+}
+
+// snapshotState monta o scanState atual a partir do progresso de cada root.
+func (sc *SubCombination) snapshotState(wordlistSHA1, domainsSHA1 string) *scanState {
+	state := &scanState{
+		WordlistSHA1: wordlistSHA1,
+		DomainsSHA1:  domainsSHA1,
+		LastIndex:    make(map[string]int),
+	}
+	sc.rootProgress.Range(func(key, value interface{}) bool {
+		state.LastIndex[key.(string)] = value.(*rootProgress).committedIndex()
+		return true
+	})
+	return state
+}